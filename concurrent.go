@@ -0,0 +1,428 @@
+// A golang Skip List Implementation.
+// https://github.com/huandu/skiplist/
+
+package skiplist
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrArenaFull is returned by ConcurrentSkipList.Add when the backing arena
+// has no room left for a new node. The arena never grows, so callers
+// should rotate to a fresh ConcurrentSkipList instead, the way an LSM
+// engine rotates memtables. Note that repeated overwrites of the same keys
+// consume arena space too (see Add), so a workload heavy on updates can hit
+// ErrArenaFull well before the live key/value bytes would suggest.
+var ErrArenaFull = errors.New("skiplist: arena is full")
+
+const (
+	concurrentMaxHeight = 20
+	concurrentP         = 1 / math.E
+)
+
+// arena is a bump-pointer byte slab that concurrentNodes are carved out of.
+// Allocating from it is lock-free (a single atomic add); it never grows,
+// so callers see ErrArenaFull instead of paying for a reallocation.
+type arena struct {
+	buf    []byte
+	offset uint32
+}
+
+func newArena(size int) *arena {
+	return &arena{buf: make([]byte, size)}
+}
+
+// alloc bump-allocates size bytes, 4-byte aligned so it's safe to reinterpret
+// the returned offset as a *concurrentNode.
+func (a *arena) alloc(size uint32) (uint32, error) {
+	offset := atomic.AddUint32(&a.offset, size+3)
+	aligned := (offset - size) &^ 3
+
+	if int(offset) > len(a.buf) {
+		return 0, ErrArenaFull
+	}
+
+	return aligned, nil
+}
+
+func (a *arena) putBytes(b []byte) (uint32, error) {
+	offset, err := a.alloc(uint32(len(b)))
+	if err != nil {
+		return 0, err
+	}
+
+	copy(a.buf[offset:], b)
+	return offset, nil
+}
+
+func (a *arena) putNode(height int) (uint32, *concurrentNode, error) {
+	size := uint32(unsafe.Offsetof(concurrentNode{}.tower)) + uint32(height)*4
+	offset, err := a.alloc(size)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return offset, a.node(offset), nil
+}
+
+func (a *arena) node(offset uint32) *concurrentNode {
+	return (*concurrentNode)(unsafe.Pointer(&a.buf[offset]))
+}
+
+func (a *arena) bytes(offset, size uint32) []byte {
+	return a.buf[offset : offset+size : offset+size]
+}
+
+func (a *arena) size() int64 {
+	// a failed alloc still bumps offset past the end of buf.
+	if offset := int64(atomic.LoadUint32(&a.offset)); offset < int64(len(a.buf)) {
+		return offset
+	}
+
+	return int64(len(a.buf))
+}
+
+// concurrentNode is laid out inline in the arena: the fixed header fields
+// above, immediately followed by a tower of exactly height uint32 offsets
+// (only height*4 of the declared [concurrentMaxHeight]uint32 bytes are ever
+// allocated for a given node). Referencing towers by offset instead of
+// *Element keeps nodes cache-dense and off the GC's radar.
+type concurrentNode struct {
+	keyOffset   uint32
+	keySize     uint32
+	valueOffset uint32
+	valueSize   uint32
+	tombstone   uint32
+	tower       [concurrentMaxHeight]uint32
+}
+
+// ConcurrentSkipList is a lock-free skip list safe for many concurrent
+// readers and writers, modeled on the inline skip lists used by Badger and
+// Pebble memtables. Keys and values are []byte, copied into an arena on
+// insert; nodes are referenced by uint32 arena offsets rather than
+// pointers. Add splices a node in with a CAS per level; Get/Delete are
+// CAS-coordinated, with deletion implemented as tombstone marking on
+// level 0 rather than physical unlinking.
+type ConcurrentSkipList struct {
+	arena  *arena
+	cmp    func(a, b []byte) int
+	head   [concurrentMaxHeight]uint32
+	height int32
+}
+
+// NewConcurrent creates a ConcurrentSkipList backed by an arena of
+// arenaSize bytes. cmp orders keys the same way bytes.Compare does:
+// negative if a < b, zero if equal, positive if a > b.
+func NewConcurrent(arenaSize int, cmp func(a, b []byte) int) *ConcurrentSkipList {
+	return &ConcurrentSkipList{
+		arena:  newArena(arenaSize),
+		cmp:    cmp,
+		height: 1,
+	}
+}
+
+// Size reports how many bytes of the arena have been used.
+func (s *ConcurrentSkipList) Size() int64 {
+	return s.arena.size()
+}
+
+func (s *ConcurrentSkipList) randomHeight() int {
+	h := 1
+
+	for h < concurrentMaxHeight && rand.Float64() < concurrentP {
+		h++
+	}
+
+	return h
+}
+
+func (s *ConcurrentSkipList) raiseHeight(h int) {
+	for {
+		cur := atomic.LoadInt32(&s.height)
+
+		if h <= int(cur) || atomic.CompareAndSwapInt32(&s.height, cur, int32(h)) {
+			return
+		}
+	}
+}
+
+// towerSlot returns the address of the level-th next pointer owned by the
+// node at offset (or by the list head, when offset is 0), so callers can
+// atomically load or CAS it.
+func (s *ConcurrentSkipList) towerSlot(offset uint32, level int) *uint32 {
+	if offset == 0 {
+		return &s.head[level]
+	}
+
+	return &s.arena.node(offset).tower[level]
+}
+
+func (s *ConcurrentSkipList) next(offset uint32, level int) uint32 {
+	return atomic.LoadUint32(s.towerSlot(offset, level))
+}
+
+func (s *ConcurrentSkipList) key(offset uint32) []byte {
+	nd := s.arena.node(offset)
+	return s.arena.bytes(nd.keyOffset, nd.keySize)
+}
+
+func (s *ConcurrentSkipList) value(offset uint32) []byte {
+	nd := s.arena.node(offset)
+	return s.arena.bytes(nd.valueOffset, nd.valueSize)
+}
+
+// findSplice walks every level from the current height down to 0, filling
+// preds/succs with the last node before key and the first node not before
+// key at that level. It also reports an exact match, found regardless of
+// whether that match is tombstoned, so Add can decide whether to overwrite.
+func (s *ConcurrentSkipList) findSplice(key []byte, preds, succs *[concurrentMaxHeight]uint32) (foundOffset uint32, exact bool) {
+	pred := uint32(0)
+
+	for level := int(atomic.LoadInt32(&s.height)) - 1; level >= 0; level-- {
+		next := s.next(pred, level)
+
+		for next != 0 {
+			cmp := s.cmp(s.key(next), key)
+
+			if cmp >= 0 {
+				if cmp == 0 {
+					exact, foundOffset = true, next
+				}
+
+				break
+			}
+
+			pred, next = next, s.next(next, level)
+		}
+
+		preds[level], succs[level] = pred, next
+	}
+
+	return
+}
+
+// newNode allocates a node carrying value and a key made of the keySize
+// bytes starting at keyOffset. Passing an existing node's keyOffset/keySize
+// (rather than putting a fresh copy of key) is how Add avoids re-copying
+// key bytes on a same-key overwrite.
+func (s *ConcurrentSkipList) newNode(keyOffset, keySize uint32, value []byte, height int) (uint32, *concurrentNode, error) {
+	valueOffset, err := s.arena.putBytes(value)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	ndOffset, nd, err := s.arena.putNode(height)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	nd.keyOffset = keyOffset
+	nd.keySize = keySize
+	nd.valueOffset = valueOffset
+	nd.valueSize = uint32(len(value))
+	return ndOffset, nd, nil
+}
+
+// Add inserts key/value, overwriting any existing entry for key. Concurrent
+// Add/Delete calls for other keys never block this one; a concurrent Add
+// for the same key races safely, with the last CAS to land winning.
+//
+// Overwriting an existing key tombstones the old node and splices in a new
+// one rather than updating the value in place; the old node's arena space,
+// including its copy of the key, is never reclaimed. The new node reuses
+// the old node's key bytes instead of copying key again, but every
+// overwrite still costs a fresh value + node header, and dead tombstoned
+// nodes stay in the level-0 chain forever. Arenas that see many repeated
+// overwrites of the same keys will fill up, and searches will slow down,
+// well before the live key/value bytes alone would predict — rotate to a
+// fresh ConcurrentSkipList on ErrArenaFull rather than waiting it out.
+func (s *ConcurrentSkipList) Add(key, value []byte) error {
+	var preds, succs [concurrentMaxHeight]uint32
+
+	height := s.randomHeight()
+	s.raiseHeight(height)
+
+	for {
+		foundOffset, exact := s.findSplice(key, &preds, &succs)
+
+		var keyOffset, keySize uint32
+
+		if exact {
+			// Logically overwrite: reuse the live node's key bytes, tombstone
+			// it, and splice the fresh value in right before it, so the next
+			// search reaches the new node first and never sees the tombstone.
+			old := s.arena.node(foundOffset)
+			keyOffset, keySize = old.keyOffset, old.keySize
+			atomic.StoreUint32(&old.tombstone, 1)
+		} else {
+			var err error
+
+			keyOffset, err = s.arena.putBytes(key)
+			if err != nil {
+				return err
+			}
+
+			keySize = uint32(len(key))
+		}
+
+		ndOffset, nd, err := s.newNode(keyOffset, keySize, value, height)
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < height; i++ {
+			nd.tower[i] = succs[i]
+		}
+
+		if !atomic.CompareAndSwapUint32(s.towerSlot(preds[0], 0), succs[0], ndOffset) {
+			// level 0 changed under us; the node we allocated is simply
+			// abandoned in the arena and we retry with a fresh splice.
+			continue
+		}
+
+		for i := 1; i < height; i++ {
+			for !atomic.CompareAndSwapUint32(s.towerSlot(preds[i], i), succs[i], ndOffset) {
+				s.findSplice(key, &preds, &succs)
+				nd.tower[i] = succs[i]
+			}
+		}
+
+		return nil
+	}
+}
+
+// Get returns the value for key and whether it was found. A tombstoned
+// (deleted) entry is reported as not found.
+func (s *ConcurrentSkipList) Get(key []byte) ([]byte, bool) {
+	var preds, succs [concurrentMaxHeight]uint32
+
+	foundOffset, exact := s.findSplice(key, &preds, &succs)
+	if !exact || atomic.LoadUint32(&s.arena.node(foundOffset).tombstone) != 0 {
+		return nil, false
+	}
+
+	return s.value(foundOffset), true
+}
+
+// Delete logically removes key by marking its node tombstoned. Reports
+// whether a live entry was found and deleted.
+func (s *ConcurrentSkipList) Delete(key []byte) bool {
+	var preds, succs [concurrentMaxHeight]uint32
+
+	foundOffset, exact := s.findSplice(key, &preds, &succs)
+	if !exact {
+		return false
+	}
+
+	return atomic.CompareAndSwapUint32(&s.arena.node(foundOffset).tombstone, 0, 1)
+}
+
+// Iterator is a forward/backward cursor over a ConcurrentSkipList. It's
+// safe to use concurrently with Add/Delete on the same list, since it only
+// ever observes nodes after they're atomically published.
+type Iterator struct {
+	list   *ConcurrentSkipList
+	offset uint32
+}
+
+// NewIter returns an iterator positioned before the first entry; call
+// SeekGE or SeekLT to position it before reading.
+func (s *ConcurrentSkipList) NewIter() *Iterator {
+	return &Iterator{list: s}
+}
+
+// SeekGE positions the iterator at the first live key greater than or
+// equal to key.
+func (it *Iterator) SeekGE(key []byte) bool {
+	var preds, succs [concurrentMaxHeight]uint32
+
+	foundOffset, exact := it.list.findSplice(key, &preds, &succs)
+
+	if exact {
+		it.offset = foundOffset
+	} else {
+		it.offset = succs[0]
+	}
+
+	return it.advanceToLive()
+}
+
+// SeekLT positions the iterator at the last live key strictly less than
+// key. The arena variant keeps no level-0 back-pointers, so a tombstoned
+// predecessor is skipped by re-splicing on its key: if the newest copy of
+// that key is live the iterator lands on it, otherwise it walks on to the
+// node before that key, the same way advanceToLive walks forward over
+// tombstones.
+func (it *Iterator) SeekLT(key []byte) bool {
+	var preds, succs [concurrentMaxHeight]uint32
+
+	it.list.findSplice(key, &preds, &succs)
+	candidate := preds[0]
+
+	for candidate != 0 && atomic.LoadUint32(&it.list.arena.node(candidate).tombstone) != 0 {
+		// an overwrite leaves tombstoned older copies of a key right behind
+		// its newest copy, which may still be live.
+		foundOffset, exact := it.list.findSplice(it.list.key(candidate), &preds, &succs)
+		if exact && atomic.LoadUint32(&it.list.arena.node(foundOffset).tombstone) == 0 {
+			candidate = foundOffset
+			break
+		}
+
+		candidate = preds[0]
+	}
+
+	it.offset = candidate
+	return it.offset != 0
+}
+
+// Next advances the iterator to the next live key.
+func (it *Iterator) Next() bool {
+	if it.offset == 0 {
+		return false
+	}
+
+	it.offset = it.list.next(it.offset, 0)
+	return it.advanceToLive()
+}
+
+// Prev moves the iterator to the previous live key by reseeking from the
+// current key.
+func (it *Iterator) Prev() bool {
+	if it.offset == 0 {
+		return false
+	}
+
+	key := append([]byte(nil), it.list.key(it.offset)...)
+	return it.SeekLT(key)
+}
+
+// Key returns the key the iterator is positioned on.
+func (it *Iterator) Key() []byte {
+	return it.list.key(it.offset)
+}
+
+// Value returns the value the iterator is positioned on.
+func (it *Iterator) Value() []byte {
+	return it.list.value(it.offset)
+}
+
+// Valid reports whether the iterator is positioned on a live entry.
+func (it *Iterator) Valid() bool {
+	return it.offset != 0
+}
+
+func (it *Iterator) advanceToLive() bool {
+	for it.offset != 0 {
+		if atomic.LoadUint32(&it.list.arena.node(it.offset).tombstone) == 0 {
+			return true
+		}
+
+		it.offset = it.list.next(it.offset, 0)
+	}
+
+	return false
+}
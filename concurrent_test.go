@@ -0,0 +1,143 @@
+package skiplist
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newConcurrentList() *ConcurrentSkipList {
+	return NewConcurrent(1<<20, bytes.Compare)
+}
+
+func TestConcurrentAddGetDelete(t *testing.T) {
+	list := newConcurrentList()
+
+	list.Add([]byte("b"), []byte("2"))
+	list.Add([]byte("a"), []byte("1"))
+	list.Add([]byte("c"), []byte("3"))
+
+	for _, c := range []struct{ key, value string }{
+		{"a", "1"}, {"b", "2"}, {"c", "3"},
+	} {
+		got, ok := list.Get([]byte(c.key))
+		if !ok || string(got) != c.value {
+			t.Errorf("Get(%q) = (%q, %v), want (%q, true)", c.key, got, ok, c.value)
+		}
+	}
+
+	if _, ok := list.Get([]byte("missing")); ok {
+		t.Errorf("Get(missing) found a value, want not found")
+	}
+
+	if !list.Delete([]byte("b")) {
+		t.Fatal("Delete(b) = false, want true")
+	}
+	if _, ok := list.Get([]byte("b")); ok {
+		t.Errorf("Get(b) after Delete found a value, want not found")
+	}
+	if list.Delete([]byte("b")) {
+		t.Errorf("Delete(b) twice = true, want false")
+	}
+}
+
+func TestConcurrentOverwrite(t *testing.T) {
+	list := newConcurrentList()
+
+	list.Add([]byte("k"), []byte("v1"))
+	list.Add([]byte("k"), []byte("v2"))
+
+	got, ok := list.Get([]byte("k"))
+	if !ok || string(got) != "v2" {
+		t.Fatalf("Get(k) = (%q, %v), want (v2, true)", got, ok)
+	}
+}
+
+func TestConcurrentOverwriteReusesKeyBytes(t *testing.T) {
+	list := newConcurrentList()
+	bigKey := bytes.Repeat([]byte{'k'}, 4096)
+
+	list.Add(bigKey, []byte("v1"))
+	beforeOverwrite := list.Size()
+
+	list.Add(bigKey, []byte("v2"))
+	overwriteGrowth := list.Size() - beforeOverwrite
+
+	if overwriteGrowth >= int64(len(bigKey)) {
+		t.Errorf("overwriting an existing key grew the arena by %d bytes, want well under the %d-byte key size (the key shouldn't be re-copied)", overwriteGrowth, len(bigKey))
+	}
+}
+
+func TestConcurrentIterator(t *testing.T) {
+	list := newConcurrentList()
+
+	for _, k := range []string{"b", "d", "a", "c"} {
+		list.Add([]byte(k), []byte(k))
+	}
+	list.Delete([]byte("c"))
+
+	it := list.NewIter()
+
+	var got []string
+	for ok := it.SeekGE([]byte("")); ok; ok = it.Next() {
+		got = append(got, string(it.Key()))
+	}
+
+	want := []string{"a", "b", "d"}
+	if len(got) != len(want) {
+		t.Fatalf("iterated %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("element %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestConcurrentSeekLTAndPrev(t *testing.T) {
+	list := newConcurrentList()
+
+	for _, k := range []string{"a", "b", "c", "d"} {
+		list.Add([]byte(k), []byte(k))
+	}
+
+	it := list.NewIter()
+	if !it.SeekLT([]byte("c")) || string(it.Key()) != "b" {
+		t.Fatalf("SeekLT(c) landed on %q, want b", it.Key())
+	}
+	if !it.Prev() || string(it.Key()) != "a" {
+		t.Fatalf("Prev() landed on %q, want a", it.Key())
+	}
+	if it.Prev() {
+		t.Errorf("Prev() past the first element should return false")
+	}
+}
+
+func TestConcurrentSeekLTSkipsTombstones(t *testing.T) {
+	list := newConcurrentList()
+
+	for _, k := range []string{"a", "b", "c", "d"} {
+		list.Add([]byte(k), []byte(k))
+	}
+	list.Delete([]byte("b"))
+
+	it := list.NewIter()
+	if !it.SeekLT([]byte("c")) || string(it.Key()) != "a" {
+		t.Fatalf("SeekLT(c) landed on %q, want a (b is tombstoned)", it.Key())
+	}
+	if it.Prev() {
+		t.Errorf("Prev() past the first live element should return false, landed on %q", it.Key())
+	}
+}
+
+func TestConcurrentArenaFull(t *testing.T) {
+	list := NewConcurrent(64, bytes.Compare)
+
+	var err error
+	for i := 0; i < 100 && err == nil; i++ {
+		err = list.Add([]byte{byte(i)}, bytes.Repeat([]byte{'x'}, 32))
+	}
+
+	if err != ErrArenaFull {
+		t.Fatalf("Add on a full arena = %v, want ErrArenaFull", err)
+	}
+}
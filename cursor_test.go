@@ -0,0 +1,164 @@
+package skiplist
+
+import "testing"
+
+func TestBackAndPrev(t *testing.T) {
+	list := newIntList()
+
+	if list.Back() != nil {
+		t.Fatalf("Back() on empty list = %v, want nil", list.Back())
+	}
+
+	list.Set(20, "b")
+	list.Set(10, "a")
+	list.Set(30, "c")
+
+	back := list.Back()
+	if back == nil || back.Key() != 30 {
+		t.Fatalf("Back() = %v, want element with key 30", back)
+	}
+
+	prev := back.Prev()
+	if prev == nil || prev.Key() != 20 {
+		t.Fatalf("Back().Prev() = %v, want element with key 20", prev)
+	}
+	if prev.Prev() == nil || prev.Prev().Key() != 10 {
+		t.Fatalf("Back().Prev().Prev() = %v, want element with key 10", prev.Prev())
+	}
+	if front := prev.Prev(); front.Prev() != nil {
+		t.Errorf("Prev() before the front element = %v, want nil", front.Prev())
+	}
+
+	list.Remove(20)
+
+	back = list.Back()
+	if back == nil || back.Key() != 30 || back.Prev() == nil || back.Prev().Key() != 10 {
+		t.Fatalf("Back/Prev chain after removing the middle element is broken: back=%v prev=%v", back, back.Prev())
+	}
+
+	list.Remove(30)
+	if back := list.Back(); back == nil || back.Key() != 10 {
+		t.Fatalf("Back() after removing the tail = %v, want element with key 10", back)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	cases := []struct {
+		seek int
+		want int
+		ok   bool
+	}{
+		{20, 20, true},
+		{25, 30, true},
+		{5, 10, true},
+		{60, 0, false},
+	}
+
+	for _, c := range cases {
+		cursor := list.Seek(c.seek)
+		if cursor.Valid() != c.ok {
+			t.Errorf("Seek(%d).Valid() = %v, want %v", c.seek, cursor.Valid(), c.ok)
+			continue
+		}
+		if c.ok && cursor.Key().(int) != c.want {
+			t.Errorf("Seek(%d).Key() = %v, want %d", c.seek, cursor.Key(), c.want)
+		}
+	}
+}
+
+func TestSeekForPrev(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	cases := []struct {
+		seek int
+		want int
+		ok   bool
+	}{
+		{20, 20, true},
+		{25, 20, true},
+		{60, 50, true},
+		{5, 0, false},
+	}
+
+	for _, c := range cases {
+		cursor := list.SeekForPrev(c.seek)
+		if cursor.Valid() != c.ok {
+			t.Errorf("SeekForPrev(%d).Valid() = %v, want %v", c.seek, cursor.Valid(), c.ok)
+			continue
+		}
+		if c.ok && cursor.Key().(int) != c.want {
+			t.Errorf("SeekForPrev(%d).Key() = %v, want %d", c.seek, cursor.Key(), c.want)
+		}
+	}
+}
+
+func TestCursorNextPrev(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30} {
+		list.Set(key, key)
+	}
+
+	cursor := list.Seek(10)
+	var got []int
+	for cursor.Valid() {
+		got = append(got, cursor.Key().(int))
+		cursor.Next()
+	}
+	if len(got) != 3 || got[0] != 10 || got[1] != 20 || got[2] != 30 {
+		t.Fatalf("forward scan = %v, want [10 20 30]", got)
+	}
+
+	cursor = list.SeekForPrev(30)
+	got = nil
+	for cursor.Valid() {
+		got = append(got, cursor.Key().(int))
+		cursor.Prev()
+	}
+	if len(got) != 3 || got[0] != 30 || got[1] != 20 || got[2] != 10 {
+		t.Fatalf("backward scan = %v, want [30 20 10]", got)
+	}
+}
+
+func TestRangeFunc(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	var got []int
+	list.RangeFunc(20, 50, func(e *Element) bool {
+		got = append(got, e.Key().(int))
+		return true
+	})
+
+	want := []int{20, 30, 40}
+	if len(got) != len(want) {
+		t.Fatalf("RangeFunc visited %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("element %d = %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	got = nil
+	list.RangeFunc(20, 50, func(e *Element) bool {
+		got = append(got, e.Key().(int))
+		return e.Key().(int) < 30
+	})
+	if len(got) != 2 {
+		t.Fatalf("RangeFunc didn't stop early: visited %v", got)
+	}
+}
@@ -0,0 +1,70 @@
+package skiplist
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestProbabilityOption(t *testing.T) {
+	list := New(greaterInt, WithProbability(0.9))
+
+	if list.p != 0.9 {
+		t.Errorf("p = %v, want 0.9", list.p)
+	}
+}
+
+func TestSetProbabilityRejectsOutOfRange(t *testing.T) {
+	list := newIntList()
+
+	for _, p := range []float64{0, 1, -0.5, 1.5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("SetProbability(%v) did not panic", p)
+				}
+			}()
+
+			list.SetProbability(p)
+		}()
+	}
+}
+
+func TestRandSourceOptionIsDeterministic(t *testing.T) {
+	listA := New(greaterInt, WithRandSource(rand.NewSource(42)))
+	listB := New(greaterInt, WithRandSource(rand.NewSource(42)))
+
+	for i := 0; i < 100; i++ {
+		listA.Set(i, i)
+		listB.Set(i, i)
+	}
+
+	// Same seed and same insertion order must produce identical tower
+	// heights at every level, since randLevel draws solely from list.rnd.
+	for a, b := listA.Front(), listB.Front(); a != nil && b != nil; a, b = a.Next(), b.Next() {
+		if len(a.next) != len(b.next) {
+			t.Fatalf("key %v: listA/listB diverge in tower height (%d vs %d)", a.Key(), len(a.next), len(b.next))
+		}
+	}
+}
+
+func TestSetRandSourceChangesFutureLevels(t *testing.T) {
+	list := newIntList()
+	list.SetRandSource(rand.NewSource(1))
+
+	levels := make([]int, 64)
+	for i := range levels {
+		levels[i] = list.randLevel()
+		if levels[i] < 1 || levels[i] > list.level {
+			t.Fatalf("randLevel() = %d, want value in [1, %d]", levels[i], list.level)
+		}
+	}
+
+	// Plugging in the same seed again must replay exactly the same levels,
+	// since every future level is drawn from the new source.
+	list.SetRandSource(rand.NewSource(1))
+	for i, want := range levels {
+		if got := list.randLevel(); got != want {
+			t.Fatalf("randLevel() #%d after re-plugging seed 1 = %d, want %d", i, got, want)
+		}
+	}
+}
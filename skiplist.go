@@ -1,6 +1,6 @@
 // A golang Skip List Implementation.
 // https://github.com/huandu/skiplist/
-// 
+//
 // Copyright 2011, Huan Du
 // Licensed under the MIT license
 // https://github.com/huandu/skiplist/blob/master/LICENSE
@@ -11,26 +11,32 @@
 // Skip list is basically an ordered set.
 // Following code creates a skip list with int key and adds some values.
 //     list := skiplist.New(skiplist.Int)
-//     
+//
 //     // adds some elements
 //     list.Set(20, "Hello")
 //     list.Set(10, "World")
 //     list.Set(40, true)         // value can be any type
 //     list.Set(40, 1000)         // replace last element with new value
-//     
+//
 //     // try to find one
 //     e := list.Get(10)          // value is the Element with key 10
 //     _ = e.Value.(string)       // it's the "World". remember to do type cast
 //     v, ok := list.GetValue(20) // directly get value. ok is false if not exists
 //     notFound := list.Get(15)   // returns nil if key is not found
-//     
+//
 //     // remove element
 //     old := list.Remove(40)     // remove found element and returns its pointer
 //                                // returns nil if key is not found
-//     
+//
 //     // re-init list. it will make the list empty.
 //     list.Init()
 //
+//     // rank / range queries, backed by span counters on every level
+//     rank := list.Rank(20)                 // 1-based position of key 20, 0 if absent
+//     e = list.ElementByRank(rank)           // the element at that position
+//     window := list.RangeByRank(0, -1)      // every element, Python-slice style
+//     window = list.RangeByKey(10, 20)       // every element with 10 <= key <= 20
+//
 // Skip list elements have random number of next pointers. The max number (say
 // "max level") is configurable.
 //
@@ -41,7 +47,7 @@
 //     list.SetMaxLevel(10)
 // Remember the side effect when changing this max level value.
 // See its wikipedia page for more details.
-//     
+//
 // Most comparable built-in types are pre-defined in skiplist, including
 //     byte []byte float32 float64 int int16 int32 int64 int8
 //     rune string uint uint16 uint32 uint64 uint8 uintptr
@@ -61,6 +67,85 @@ import (
 	"math/rand"
 )
 
+// GreaterThanFunc is the key order function of a skip list.
+// It must return true if lhs is logically greater than rhs.
+type GreaterThanFunc func(lhs, rhs interface{}) bool
+
+// DefaultMaxLevel is the default max level for a skip list created by New().
+// Changing it doesn't affect skip lists already created.
+var DefaultMaxLevel = 24
+
+// DefaultProbability is the default level-generation probability factor p
+// for a skip list created by New(). Changing it doesn't affect skip lists
+// already created.
+var DefaultProbability = 0.25
+
+// elementNode is the shared head of SkipList and Element. Besides the
+// forward pointers, span[i] counts how many level-0 positions next[i]
+// advances by (1 between adjacent nodes; for a nil next[i], the number of
+// nodes after this one), which is what makes Rank/ElementByRank run in
+// O(log n) instead of walking the level-0 chain.
+type elementNode struct {
+	next []*Element
+	span []int
+}
+
+// Element is a node of a skip list.
+type Element struct {
+	elementNode
+	key   interface{}
+	Value interface{}
+	prev  *Element
+}
+
+// Next returns the next adjacent element. Returns nil if current element is
+// the last one.
+func (element *Element) Next() *Element {
+	return element.next[0]
+}
+
+// Prev returns the previous adjacent element. Returns nil if current element
+// is the first one.
+func (element *Element) Prev() *Element {
+	return element.prev
+}
+
+// Key returns the key of current element.
+func (element *Element) Key() interface{} {
+	return element.key
+}
+
+// SkipList is a skip list.
+type SkipList struct {
+	elementNode
+	level   int
+	length  int
+	tail    *Element
+	keyFunc GreaterThanFunc
+	p       float64
+	rnd     *rand.Rand
+}
+
+// Option configures optional SkipList parameters at construction time.
+type Option func(*SkipList)
+
+// WithProbability sets the level-generation probability factor p a list is
+// created with, overriding DefaultProbability. See SetProbability for the
+// constraints on p.
+func WithProbability(p float64) Option {
+	return func(list *SkipList) {
+		list.SetProbability(p)
+	}
+}
+
+// WithRandSource sets the source of randomness a list is created with,
+// overriding the default private *rand.Rand. See SetRandSource.
+func WithRandSource(src rand.Source) Option {
+	return func(list *SkipList) {
+		list.SetRandSource(src)
+	}
+}
+
 // Creates a new skiplist.
 // keyFunc is a func checking the "greater than" logic.
 // If k1 equals k2, keyFunc(k1, k2) and keyFunc(k2, k1) must both be false.
@@ -68,26 +153,74 @@ import (
 // For instance, skiplist.Int is for the list with int keys.
 // By default, the list with built-in type key is in ascend order.
 // The keyFunc named as skiplist.IntReversed is for descend key order list.
-func New(keyFunc GreaterThanFunc) *SkipList {
+// Opts can tweak the level-generation probability (WithProbability) or
+// plug in a custom random source (WithRandSource).
+func New(keyFunc GreaterThanFunc, opts ...Option) *SkipList {
 	if DefaultMaxLevel <= 0 {
 		panic("skiplist default level must not be zero or negative")
 	}
 
-	return &SkipList{
-		level:       DefaultMaxLevel,
-		elementNode: elementNode{next: make([]*Element, DefaultMaxLevel)},
-		keyFunc:     keyFunc,
+	list := &SkipList{
+		level: DefaultMaxLevel,
+		elementNode: elementNode{
+			next: make([]*Element, DefaultMaxLevel),
+			span: make([]int, DefaultMaxLevel),
+		},
+		keyFunc: keyFunc,
+		p:       DefaultProbability,
+		rnd:     rand.New(rand.NewSource(rand.Int63())),
+	}
+
+	for _, opt := range opts {
+		opt(list)
+	}
+
+	return list
+}
+
+// SetProbability sets the geometric-distribution probability factor p used
+// by randLevel: each new element's level is 1 plus the number of successful
+// p-weighted coin flips, capped at MaxLevel(). The classic value, matching
+// Pugh's paper and the Redis/LevelDB implementations, is 1/4. Together with
+// MaxLevel, p determines the list's effective capacity before every node
+// saturates to the max level, roughly 1/p^MaxLevel. Panics if p is not
+// strictly between 0 and 1.
+func (list *SkipList) SetProbability(p float64) {
+	if p <= 0 || p >= 1 {
+		panic("skiplist probability must be strictly between 0 and 1")
 	}
+
+	list.p = p
+}
+
+// SetRandSource plugs in a custom source of randomness for level
+// generation, replacing the list's private *rand.Rand. Use this for
+// deterministic or thread-local RNGs; by default each list already owns
+// its own *rand.Rand seeded off the global one, so concurrent lists don't
+// contend on math/rand's global lock.
+func (list *SkipList) SetRandSource(src rand.Source) {
+	list.rnd = rand.New(src)
 }
 
-func randLevel(level int) []*Element {
-	return make([]*Element, rand.Intn(level)+1)
+// randLevel picks a random height for a new element using the classic
+// geometric distribution: keep climbing while a p-weighted coin flip
+// succeeds, capped at the list's max level.
+func (list *SkipList) randLevel() int {
+	level := 1
+
+	for level < list.level && list.rnd.Float64() < list.p {
+		level++
+	}
+
+	return level
 }
 
 // Resets a skiplist and discards all exists elements.
 func (list *SkipList) Init() *SkipList {
 	list.next = make([]*Element, list.level)
+	list.span = make([]int, list.level)
 	list.length = 0
+	list.tail = nil
 	return list
 }
 
@@ -96,6 +229,11 @@ func (list *SkipList) Front() *Element {
 	return list.next[0]
 }
 
+// Back returns the last element. Returns nil if the list is empty.
+func (list *SkipList) Back() *Element {
+	return list.tail
+}
+
 // Gets list length.
 func (list *SkipList) Len() int {
 	return list.length
@@ -107,7 +245,7 @@ func (list *SkipList) Len() int {
 func (list *SkipList) Set(key, value interface{}) *Element {
 	var element *Element
 
-	prevs := list.getPrevElementNodes(key)
+	prevs, ranks, prevElement := list.getPrevElementNodes(key)
 
 	// found an element with the same key, replace its value
 	if element = prevs[0].next[0]; element != nil && !list.keyFunc(element.key, key) {
@@ -115,14 +253,37 @@ func (list *SkipList) Set(key, value interface{}) *Element {
 		return element
 	}
 
+	height := list.randLevel()
 	element = &Element{
-		elementNode: elementNode{next: randLevel(list.level)},
-		key:         key,
-		Value:       value,
+		elementNode: elementNode{
+			next: make([]*Element, height),
+			span: make([]int, height),
+		},
+		key:   key,
+		Value: value,
+		prev:  prevElement,
+	}
+
+	for i := 0; i < height; i++ {
+		// split the span prevs[i] used to own into the part before the new
+		// node (which prevs[i] keeps) and the part after (which the new
+		// node inherits), same trick as Redis' zslInsert.
+		element.span[i] = prevs[i].span[i] - (ranks[0] - ranks[i])
+		prevs[i].span[i] = ranks[0] - ranks[i] + 1
+
+		element.next[i] = prevs[i].next[i]
+		prevs[i].next[i] = element
+	}
+
+	// levels taller than the new node only grow by the one level-0 hop it adds.
+	for i := height; i < list.level; i++ {
+		prevs[i].span[i]++
 	}
 
-	for i := range element.next {
-		element.next[i], prevs[i].next[i] = prevs[i].next[i], element
+	if successor := element.next[0]; successor != nil {
+		successor.prev = element
+	} else {
+		list.tail = element
 	}
 
 	list.length++
@@ -164,15 +325,242 @@ func (list *SkipList) GetValue(key interface{}) (interface{}, bool) {
 	return element.Value, true
 }
 
+// Rank returns the 1-based position of key in the ascending order defined by
+// the list's keyFunc. Returns 0 if key doesn't exist.
+func (list *SkipList) Rank(key interface{}) int {
+	var prev *elementNode = &list.elementNode
+	var next, last *Element
+
+	rank := 0
+
+	for i := list.level - 1; i >= 0; i-- {
+		next = prev.next[i]
+
+		// same strict-less-than descent as Get/getPrevElementNodes: stop at
+		// the first node whose key is >= key, rather than advancing past it.
+		for next != last && list.keyFunc(key, next.key) {
+			rank += prev.span[i]
+			prev, next = &next.elementNode, next.next[i]
+		}
+
+		last = next
+	}
+
+	if last == nil || list.keyFunc(last.key, key) || list.keyFunc(key, last.key) {
+		return 0
+	}
+
+	// rank only counts nodes strictly before last; last itself, the match,
+	// is one more hop, and a level-0 span between adjacent nodes is always 1.
+	return rank + 1
+}
+
+// ElementByRank returns the element at the given 1-based rank.
+// Returns nil if rank is out of [1, Len()].
+func (list *SkipList) ElementByRank(rank int) *Element {
+	if rank <= 0 {
+		return nil
+	}
+
+	var prev *elementNode = &list.elementNode
+	var found *Element
+
+	traversed := 0
+
+	for i := list.level - 1; i >= 0; i-- {
+		for prev.next[i] != nil && traversed+prev.span[i] <= rank {
+			traversed += prev.span[i]
+			found = prev.next[i]
+			prev = &found.elementNode
+		}
+
+		if traversed == rank {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// RangeByRank returns every element whose 0-based rank falls within
+// [start, stop], inclusive on both ends. Negative indices count from the
+// tail, Python-slice style: -1 is the last element. Returns nil if the
+// range is empty or doesn't overlap the list.
+func (list *SkipList) RangeByRank(start, stop int) []*Element {
+	length := list.length
+
+	if length == 0 {
+		return nil
+	}
+
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= length {
+		stop = length - 1
+	}
+	if start > stop || start >= length {
+		return nil
+	}
+
+	result := make([]*Element, 0, stop-start+1)
+
+	for element := list.ElementByRank(start + 1); element != nil && start <= stop; start++ {
+		result = append(result, element)
+		element = element.Next()
+	}
+
+	return result
+}
+
+// RangeByKey returns every element whose key lies within [min, max],
+// inclusive, in ascending list order. Returns nil if no element matches.
+func (list *SkipList) RangeByKey(min, max interface{}) []*Element {
+	var prev *elementNode = &list.elementNode
+	var next, last *Element
+
+	for i := list.level - 1; i >= 0; i-- {
+		next = prev.next[i]
+
+		for next != last && list.keyFunc(min, next.key) {
+			prev, next = &next.elementNode, next.next[i]
+		}
+
+		last = next
+	}
+
+	var result []*Element
+
+	for element := last; element != nil && !list.keyFunc(element.key, max); element = element.Next() {
+		result = append(result, element)
+	}
+
+	return result
+}
+
+// Cursor is a bidirectional iterator over a SkipList's elements, positioned
+// by Seek or SeekForPrev. A zero Cursor is not valid.
+type Cursor struct {
+	list    *SkipList
+	element *Element
+}
+
+// Seek returns a cursor positioned at the first element whose key is
+// greater than or equal to key. The cursor is invalid if no such element
+// exists.
+func (list *SkipList) Seek(key interface{}) *Cursor {
+	var prev *elementNode = &list.elementNode
+	var next, last *Element
+
+	for i := list.level - 1; i >= 0; i-- {
+		next = prev.next[i]
+
+		for next != last && list.keyFunc(key, next.key) {
+			prev, next = &next.elementNode, next.next[i]
+		}
+
+		last = next
+	}
+
+	return &Cursor{list: list, element: last}
+}
+
+// SeekForPrev returns a cursor positioned at the last element whose key is
+// less than or equal to key. The cursor is invalid if no such element
+// exists.
+func (list *SkipList) SeekForPrev(key interface{}) *Cursor {
+	cursor := list.Seek(key)
+
+	if cursor.element != nil && !list.keyFunc(cursor.element.key, key) && !list.keyFunc(key, cursor.element.key) {
+		return cursor
+	}
+
+	if cursor.element == nil {
+		cursor.element = list.tail
+	} else {
+		cursor.element = cursor.element.prev
+	}
+
+	return cursor
+}
+
+// Valid reports whether the cursor is positioned on an element.
+func (cursor *Cursor) Valid() bool {
+	return cursor.element != nil
+}
+
+// Key returns the key of the element the cursor is positioned on.
+// Must not be called unless Valid() is true.
+func (cursor *Cursor) Key() interface{} {
+	return cursor.element.key
+}
+
+// Value returns the value of the element the cursor is positioned on.
+// Must not be called unless Valid() is true.
+func (cursor *Cursor) Value() interface{} {
+	return cursor.element.Value
+}
+
+// Next moves the cursor to the next element in ascending key order.
+func (cursor *Cursor) Next() {
+	if cursor.element != nil {
+		cursor.element = cursor.element.next[0]
+	}
+}
+
+// Prev moves the cursor to the previous element in ascending key order.
+func (cursor *Cursor) Prev() {
+	if cursor.element != nil {
+		cursor.element = cursor.element.prev
+	}
+}
+
+// RangeFunc scans elements with key in the half-open range [from, to),
+// calling fn for each one in ascending order until fn returns false.
+func (list *SkipList) RangeFunc(from, to interface{}, fn func(*Element) bool) {
+	for cursor := list.Seek(from); cursor.Valid() && list.keyFunc(to, cursor.Key()); cursor.Next() {
+		if !fn(cursor.element) {
+			return
+		}
+	}
+}
+
 // Removes an element.
 // Returns removed element pointer if found, nil if not found.
 func (list *SkipList) Remove(key interface{}) *Element {
-	prevs := list.getPrevElementNodes(key)
+	prevs, _, _ := list.getPrevElementNodes(key)
 
 	// found the element, remove it
 	if element := prevs[0].next[0]; element != nil && !list.keyFunc(element.key, key) {
-		for k, v := range element.next {
-			prevs[k].next[k] = v
+		height := len(element.next)
+
+		// SetMaxLevel may have shrunk the list below element's own tower
+		// height since it was inserted; prevs only has list.level entries,
+		// so never index past that even though the tower itself is taller.
+		linked := height
+		if linked > list.level {
+			linked = list.level
+		}
+
+		for i := 0; i < linked; i++ {
+			prevs[i].next[i] = element.next[i]
+			prevs[i].span[i] += element.span[i] - 1
+		}
+
+		for i := linked; i < list.level; i++ {
+			prevs[i].span[i]--
+		}
+
+		if successor := element.next[0]; successor != nil {
+			successor.prev = element.prev
+		} else {
+			list.tail = element.prev
 		}
 
 		list.length--
@@ -182,23 +570,34 @@ func (list *SkipList) Remove(key interface{}) *Element {
 	return nil
 }
 
-func (list *SkipList) getPrevElementNodes(key interface{}) []*elementNode {
+// getPrevElementNodes returns, for every level, the node right before key,
+// the rank (1-based level-0 position, 0 for the list head) it sits at,
+// and the level-0 predecessor element itself (nil if key belongs at the
+// front of the list). The ranks are what let Set/Remove update span
+// counters in place; the predecessor is what lets them maintain prev links.
+func (list *SkipList) getPrevElementNodes(key interface{}) ([]*elementNode, []int, *Element) {
 	var prev *elementNode = &list.elementNode
+	var prevElement *Element
 	var next, last *Element
 
 	prevs := make([]*elementNode, list.level)
+	ranks := make([]int, list.level)
+
+	rank := 0
 
 	for i := list.level - 1; i >= 0; i-- {
 		next = prev.next[i]
 
 		for next != last && list.keyFunc(key, next.key) {
+			rank += prev.span[i]
+			prevElement = next
 			prev, next = &next.elementNode, next.next[i]
 		}
 
-		prevs[i], last = prev, next
+		prevs[i], ranks[i], last = prev, rank, next
 	}
 
-	return prevs
+	return prevs, ranks, prevElement
 }
 
 // Gets current max level value.
@@ -221,10 +620,18 @@ func (list *SkipList) SetMaxLevel(level int) (old int) {
 
 	if old > level {
 		list.next = list.next[:level]
+		list.span = list.span[:level]
 		return
 	}
 
-	nils := make([]*Element, level-old)
-	list.next = append(list.next, nils...)
+	list.next = append(list.next, make([]*Element, level-old)...)
+
+	// a newly added level has no node tall enough to reach it yet, so its
+	// head span simply spans the whole list until some node grows into it.
+	newSpans := make([]int, level-old)
+	for i := range newSpans {
+		newSpans[i] = list.length
+	}
+	list.span = append(list.span, newSpans...)
 	return
-}
\ No newline at end of file
+}
@@ -0,0 +1,223 @@
+package skiplist
+
+import "testing"
+
+func greaterInt(lhs, rhs interface{}) bool {
+	return lhs.(int) > rhs.(int)
+}
+
+func newIntList() *SkipList {
+	return New(greaterInt)
+}
+
+func TestRank(t *testing.T) {
+	list := newIntList()
+
+	if rank := list.Rank(10); rank != 0 {
+		t.Fatalf("Rank on empty list = %d, want 0", rank)
+	}
+
+	list.Set(30, "c")
+	list.Set(10, "a")
+	list.Set(20, "b")
+
+	cases := []struct {
+		key  int
+		rank int
+	}{
+		{10, 1},
+		{20, 2},
+		{30, 3},
+		{15, 0}, // absent key, between 10 and 20
+		{1, 0},  // absent key, before everything
+		{99, 0}, // absent key, after everything
+	}
+
+	for _, c := range cases {
+		if rank := list.Rank(c.key); rank != c.rank {
+			t.Errorf("Rank(%d) = %d, want %d", c.key, rank, c.rank)
+		}
+	}
+}
+
+func TestRankAndElementByRankAreInverse(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{50, 10, 40, 20, 30} {
+		list.Set(key, key)
+	}
+
+	for rank := 1; rank <= list.Len(); rank++ {
+		element := list.ElementByRank(rank)
+		if element == nil {
+			t.Fatalf("ElementByRank(%d) = nil", rank)
+		}
+		if got := list.Rank(element.Key()); got != rank {
+			t.Errorf("Rank(%v) = %d, want %d", element.Key(), got, rank)
+		}
+	}
+
+	if list.ElementByRank(0) != nil {
+		t.Errorf("ElementByRank(0) should be nil")
+	}
+	if list.ElementByRank(list.Len()+1) != nil {
+		t.Errorf("ElementByRank(len+1) should be nil")
+	}
+}
+
+func TestSetDuplicateKeyKeepsRank(t *testing.T) {
+	list := newIntList()
+
+	list.Set(10, "a")
+	list.Set(20, "b")
+	list.Set(30, "c")
+
+	e := list.Set(20, "b2")
+
+	if e.Value != "b2" {
+		t.Fatalf("Set on existing key returned value %v, want b2", e.Value)
+	}
+	if list.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3 after overwriting an existing key", list.Len())
+	}
+	if rank := list.Rank(20); rank != 2 {
+		t.Errorf("Rank(20) after overwrite = %d, want 2", rank)
+	}
+}
+
+func TestRangeByRank(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	assertKeys := func(got []*Element, want []int) {
+		t.Helper()
+
+		if len(got) != len(want) {
+			t.Fatalf("got %d elements, want %d", len(got), len(want))
+		}
+
+		for i, e := range got {
+			if e.Key().(int) != want[i] {
+				t.Errorf("element %d = %v, want %d", i, e.Key(), want[i])
+			}
+		}
+	}
+
+	assertKeys(list.RangeByRank(0, 1), []int{10, 20})
+	assertKeys(list.RangeByRank(-2, -1), []int{40, 50})
+	assertKeys(list.RangeByRank(0, -1), []int{10, 20, 30, 40, 50})
+
+	if got := list.RangeByRank(10, 20); got != nil {
+		t.Errorf("out-of-range RangeByRank = %v, want nil", got)
+	}
+	if got := list.RangeByRank(3, 1); got != nil {
+		t.Errorf("start>stop RangeByRank = %v, want nil", got)
+	}
+
+	empty := newIntList()
+	if got := empty.RangeByRank(0, -1); got != nil {
+		t.Errorf("RangeByRank on empty list = %v, want nil", got)
+	}
+}
+
+func TestRangeByKey(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	got := list.RangeByKey(15, 35)
+	want := []int{20, 30}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d elements, want %d", len(got), len(want))
+	}
+
+	for i, e := range got {
+		if e.Key().(int) != want[i] {
+			t.Errorf("element %d = %v, want %d", i, e.Key(), want[i])
+		}
+	}
+
+	if got := list.RangeByKey(1000, 2000); got != nil {
+		t.Errorf("RangeByKey outside the list = %v, want nil", got)
+	}
+}
+
+func TestRemoveMaintainsSpans(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	if removed := list.Remove(30); removed == nil {
+		t.Fatal("Remove(30) = nil, want removed element")
+	}
+
+	want := []int{10, 20, 40, 50}
+	for rank, key := range want {
+		if got := list.Rank(key); got != rank+1 {
+			t.Errorf("Rank(%d) after removing 30 = %d, want %d", key, got, rank+1)
+		}
+	}
+	if got := list.Rank(30); got != 0 {
+		t.Errorf("Rank(30) after removal = %d, want 0", got)
+	}
+
+	if got := list.RangeByRank(0, -1); len(got) != len(want) {
+		t.Fatalf("RangeByRank after removal has %d elements, want %d", len(got), len(want))
+	}
+}
+
+func TestSetMaxLevelRecomputesSpans(t *testing.T) {
+	list := newIntList()
+
+	for _, key := range []int{10, 20, 30, 40, 50} {
+		list.Set(key, key)
+	}
+
+	list.SetMaxLevel(2)
+	list.SetMaxLevel(10)
+
+	for rank, key := range []int{10, 20, 30, 40, 50} {
+		if got := list.Rank(key); got != rank+1 {
+			t.Errorf("Rank(%d) after SetMaxLevel churn = %d, want %d", key, got, rank+1)
+		}
+	}
+}
+
+func TestRemoveAfterSetMaxLevelShrink(t *testing.T) {
+	list := newIntList()
+
+	for i := 0; i < 200; i++ {
+		list.Set(i, i)
+	}
+
+	// Towers built at the original level are now taller than list.level;
+	// Remove must not index past the shrunk prevs/span slices.
+	list.SetMaxLevel(1)
+
+	if removed := list.Remove(100); removed == nil || removed.Key() != 100 {
+		t.Fatalf("Remove(100) = %v, want element with key 100", removed)
+	}
+	if list.Len() != 199 {
+		t.Errorf("Len() = %d, want 199", list.Len())
+	}
+	if _, ok := list.GetValue(100); ok {
+		t.Errorf("GetValue(100) after Remove found a value, want not found")
+	}
+
+	for i := 0; i < 200; i++ {
+		if i == 100 {
+			continue
+		}
+		if _, ok := list.GetValue(i); !ok {
+			t.Errorf("GetValue(%d) after unrelated removal = not found, want found", i)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// A golang Skip List Implementation.
+// https://github.com/huandu/skiplist/
+
+package skiplist
+
+import (
+	"math/rand"
+)
+
+// Ordered is the built-in key constraint accepted by NewG. Keys are
+// compared with the standard < operator in ascending order.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// elementNodeG is the shared head of SkipListG and ElementG.
+type elementNodeG[K any, V any] struct {
+	next []*ElementG[K, V]
+}
+
+// ElementG is a node of a SkipListG.
+type ElementG[K any, V any] struct {
+	elementNodeG[K, V]
+	key   K
+	Value V
+}
+
+// Next returns the next adjacent element. Returns nil if current element is
+// the last one.
+func (element *ElementG[K, V]) Next() *ElementG[K, V] {
+	return element.next[0]
+}
+
+// Key returns the key of current element.
+func (element *ElementG[K, V]) Key() K {
+	return element.key
+}
+
+// SkipListG is the generic counterpart of SkipList: it stores typed keys
+// and values directly, avoiding the boxing and type assertions that the
+// interface{}-based SkipList pays on every Set/Get. The interface{}-based
+// SkipList is kept around unchanged for callers who can't use generics yet.
+// SkipListG shares SkipList's forward-pointer structure, its geometric level
+// distribution and the per-instance probability/RNG knobs from
+// SetProbability/SetRandSource. It keeps no span counters and no back
+// links, so it has no Rank, ElementByRank, RangeByRank, RangeByKey, Back,
+// Prev, Seek, SeekForPrev, RangeFunc or Init.
+type SkipListG[K any, V any] struct {
+	elementNodeG[K, V]
+	level  int
+	length int
+	less   func(a, b K) bool
+	p      float64
+	rnd    *rand.Rand
+}
+
+// OptionG configures optional SkipListG parameters at construction time.
+type OptionG[K any, V any] func(*SkipListG[K, V])
+
+// WithProbabilityG sets the level-generation probability factor p a list is
+// created with, overriding DefaultProbability. See SkipListG.SetProbability
+// for the constraints on p.
+func WithProbabilityG[K any, V any](p float64) OptionG[K, V] {
+	return func(list *SkipListG[K, V]) {
+		list.SetProbability(p)
+	}
+}
+
+// WithRandSourceG sets the source of randomness a list is created with,
+// overriding the default private *rand.Rand. See SkipListG.SetRandSource.
+func WithRandSourceG[K any, V any](src rand.Source) OptionG[K, V] {
+	return func(list *SkipListG[K, V]) {
+		list.SetRandSource(src)
+	}
+}
+
+// NewG creates a new generic skiplist whose keys are ordered with the
+// built-in < operator. For key types that aren't Ordered, or for a custom
+// order, use NewGFunc.
+func NewG[K Ordered, V any](opts ...OptionG[K, V]) *SkipListG[K, V] {
+	return NewGFunc[K, V](func(a, b K) bool { return a < b }, opts...)
+}
+
+// NewGFunc creates a new generic skiplist using less as the "a is ordered
+// before b" comparison. If a == b, less(a, b) and less(b, a) must both be
+// false.
+func NewGFunc[K any, V any](less func(a, b K) bool, opts ...OptionG[K, V]) *SkipListG[K, V] {
+	if DefaultMaxLevel <= 0 {
+		panic("skiplist default level must not be zero or negative")
+	}
+
+	list := &SkipListG[K, V]{
+		level:        DefaultMaxLevel,
+		elementNodeG: elementNodeG[K, V]{next: make([]*ElementG[K, V], DefaultMaxLevel)},
+		less:         less,
+		p:            DefaultProbability,
+		rnd:          rand.New(rand.NewSource(rand.Int63())),
+	}
+
+	for _, opt := range opts {
+		opt(list)
+	}
+
+	return list
+}
+
+// NewFromMap creates a new generic skiplist ordered with the built-in <
+// operator, preloaded with every entry of m.
+func NewFromMap[K Ordered, V any](m map[K]V) *SkipListG[K, V] {
+	list := NewG[K, V]()
+
+	for k, v := range m {
+		list.Set(k, v)
+	}
+
+	return list
+}
+
+// SetProbability sets the geometric-distribution probability factor p used
+// by randLevel. See SkipList.SetProbability for details; panics if p is
+// not strictly between 0 and 1.
+func (list *SkipListG[K, V]) SetProbability(p float64) {
+	if p <= 0 || p >= 1 {
+		panic("skiplist probability must be strictly between 0 and 1")
+	}
+
+	list.p = p
+}
+
+// SetRandSource plugs in a custom source of randomness for level
+// generation. See SkipList.SetRandSource for details.
+func (list *SkipListG[K, V]) SetRandSource(src rand.Source) {
+	list.rnd = rand.New(src)
+}
+
+// randLevel picks a random height for a new element using the classic
+// geometric distribution, same as SkipList.randLevel.
+func (list *SkipListG[K, V]) randLevel() int {
+	level := 1
+
+	for level < list.level && list.rnd.Float64() < list.p {
+		level++
+	}
+
+	return level
+}
+
+// Gets the first element.
+func (list *SkipListG[K, V]) Front() *ElementG[K, V] {
+	return list.next[0]
+}
+
+// Gets list length.
+func (list *SkipListG[K, V]) Len() int {
+	return list.length
+}
+
+// Sets a value in the list with key.
+// If the key exists, change element value to the new one.
+// Returns new element pointer.
+func (list *SkipListG[K, V]) Set(key K, value V) *ElementG[K, V] {
+	var element *ElementG[K, V]
+
+	prevs := list.getPrevElementNodes(key)
+
+	// found an element with the same key, replace its value
+	if element = prevs[0].next[0]; element != nil && !list.less(element.key, key) && !list.less(key, element.key) {
+		element.Value = value
+		return element
+	}
+
+	element = &ElementG[K, V]{
+		elementNodeG: elementNodeG[K, V]{next: make([]*ElementG[K, V], list.randLevel())},
+		key:          key,
+		Value:        value,
+	}
+
+	for i := range element.next {
+		element.next[i], prevs[i].next[i] = prevs[i].next[i], element
+	}
+
+	list.length++
+	return element
+}
+
+// Gets an element.
+// Returns element pointer if found, nil if not found.
+func (list *SkipListG[K, V]) Get(key K) *ElementG[K, V] {
+	var prev *elementNodeG[K, V] = &list.elementNodeG
+	var next, last *ElementG[K, V]
+
+	for i := list.level - 1; i >= 0; i-- {
+		next = prev.next[i]
+
+		for next != last && list.less(next.key, key) {
+			prev, next = &next.elementNodeG, next.next[i]
+		}
+
+		last = next
+	}
+
+	if last != nil && !list.less(last.key, key) && !list.less(key, last.key) {
+		return last
+	}
+
+	return nil
+}
+
+// Gets a value. It's a short hand for Get().Value.
+// Returns value and its existence status.
+func (list *SkipListG[K, V]) GetValue(key K) (value V, ok bool) {
+	element := list.Get(key)
+
+	if element == nil {
+		return value, false
+	}
+
+	return element.Value, true
+}
+
+// Removes an element.
+// Returns removed element pointer if found, nil if not found.
+func (list *SkipListG[K, V]) Remove(key K) *ElementG[K, V] {
+	prevs := list.getPrevElementNodes(key)
+
+	// found the element, remove it
+	if element := prevs[0].next[0]; element != nil && !list.less(element.key, key) && !list.less(key, element.key) {
+		// SetMaxLevel may have shrunk the list below element's own tower
+		// height since it was inserted; prevs only has list.level entries,
+		// so never index past that even though the tower itself is taller.
+		height := len(element.next)
+		if height > len(prevs) {
+			height = len(prevs)
+		}
+
+		for k := 0; k < height; k++ {
+			prevs[k].next[k] = element.next[k]
+		}
+
+		list.length--
+		return element
+	}
+
+	return nil
+}
+
+func (list *SkipListG[K, V]) getPrevElementNodes(key K) []*elementNodeG[K, V] {
+	var prev *elementNodeG[K, V] = &list.elementNodeG
+	var next, last *ElementG[K, V]
+
+	prevs := make([]*elementNodeG[K, V], list.level)
+
+	for i := list.level - 1; i >= 0; i-- {
+		next = prev.next[i]
+
+		for next != last && list.less(next.key, key) {
+			prev, next = &next.elementNodeG, next.next[i]
+		}
+
+		prevs[i], last = prev, next
+	}
+
+	return prevs
+}
+
+// Keys returns every key in the list, in ascending order.
+func (list *SkipListG[K, V]) Keys() []K {
+	keys := make([]K, 0, list.length)
+
+	for element := list.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.key)
+	}
+
+	return keys
+}
+
+// Values returns every value in the list, in ascending key order.
+func (list *SkipListG[K, V]) Values() []V {
+	values := make([]V, 0, list.length)
+
+	for element := list.Front(); element != nil; element = element.Next() {
+		values = append(values, element.Value)
+	}
+
+	return values
+}
+
+// Gets current max level value.
+func (list *SkipListG[K, V]) MaxLevel() int {
+	return list.level
+}
+
+// Changes skip list max level.
+// If level is not greater than 0, just panic.
+func (list *SkipListG[K, V]) SetMaxLevel(level int) (old int) {
+	if level <= 0 {
+		panic("invalid argument to SetLevel")
+	}
+
+	old, list.level = list.level, level
+
+	if old == level {
+		return
+	}
+
+	if old > level {
+		list.next = list.next[:level]
+		return
+	}
+
+	nils := make([]*ElementG[K, V], level-old)
+	list.next = append(list.next, nils...)
+	return
+}
@@ -0,0 +1,134 @@
+package skiplist
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSkipListGSetGetRemove(t *testing.T) {
+	list := NewG[int, string]()
+
+	list.Set(20, "b")
+	list.Set(10, "a")
+	list.Set(30, "c")
+
+	if v, ok := list.GetValue(10); !ok || v != "a" {
+		t.Errorf("GetValue(10) = (%q, %v), want (a, true)", v, ok)
+	}
+	if list.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", list.Len())
+	}
+	if got := list.Keys(); got[0] != 10 || got[1] != 20 || got[2] != 30 {
+		t.Errorf("Keys() = %v, want [10 20 30]", got)
+	}
+	if got := list.Values(); got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Values() = %v, want [a b c]", got)
+	}
+
+	if removed := list.Remove(20); removed == nil || removed.Value != "b" {
+		t.Fatalf("Remove(20) = %v, want element with value b", removed)
+	}
+	if _, ok := list.GetValue(20); ok {
+		t.Errorf("GetValue(20) after Remove found a value, want not found")
+	}
+}
+
+func TestSkipListGRemoveAfterSetMaxLevelShrink(t *testing.T) {
+	list := NewG[int, int]()
+
+	for i := 0; i < 500; i++ {
+		list.Set(i, i)
+	}
+
+	// Towers built at the original level are now taller than list.level;
+	// Remove must not index past the shrunk prevs slice.
+	list.SetMaxLevel(1)
+
+	for i := 0; i < 500; i++ {
+		if removed := list.Remove(i); removed == nil || removed.Key() != i {
+			t.Fatalf("Remove(%d) = %v, want element with key %d", i, removed, i)
+		}
+	}
+	if list.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", list.Len())
+	}
+}
+
+func TestSkipListGProbabilityOption(t *testing.T) {
+	list := NewG[int, int](WithProbabilityG[int, int](0.5))
+
+	if list.p != 0.5 {
+		t.Errorf("p = %v, want 0.5", list.p)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("SetProbability(1) did not panic")
+		}
+	}()
+
+	list.SetProbability(1)
+}
+
+func TestNewFromMap(t *testing.T) {
+	list := NewFromMap(map[string]int{"a": 1, "b": 2, "c": 3})
+
+	if list.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", list.Len())
+	}
+	if v, ok := list.GetValue("b"); !ok || v != 2 {
+		t.Errorf("GetValue(b) = (%d, %v), want (2, true)", v, ok)
+	}
+}
+
+func BenchmarkSkipListSetInt(b *testing.B) {
+	list := New(greaterInt)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		list.Set(i, i)
+	}
+}
+
+func BenchmarkSkipListGSetInt(b *testing.B) {
+	list := NewG[int, int]()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		list.Set(i, i)
+	}
+}
+
+func BenchmarkSkipListGetString(b *testing.B) {
+	list := New(func(lhs, rhs interface{}) bool { return lhs.(string) > rhs.(string) })
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		list.Set(keys[i], i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		list.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkSkipListGGetString(b *testing.B) {
+	list := NewG[string, int]()
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		list.Set(keys[i], i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		list.Get(keys[i%len(keys)])
+	}
+}